@@ -0,0 +1,351 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// BundleSchema detects structurally identical subschemas within schemaMap
+// and hoists them into a top-level "$defs" section, replacing each
+// occurrence with a "$ref". Subschemas are hoisted once they occur at
+// least config.BundleThreshold times, which shrinks schemas for large
+// umbrella charts and makes them navigable in IDEs.
+//
+// When config.SplitOutput is set, each hoisted def is additionally
+// written to its own file under that directory and cross-referenced with
+// an external $ref URI, alongside an index schema that $refs them all.
+func BundleSchema(config *Config, schemaMap map[string]interface{}) (map[string]interface{}, error) {
+	threshold := config.BundleThreshold
+	if threshold <= 0 {
+		threshold = 2
+	}
+
+	b := &bundler{
+		threshold: threshold,
+		counts:    map[string]int{},
+		hashNames: map[string]string{},
+	}
+
+	b.count(schemaMap, "")
+	b.demoteWrappers(schemaMap)
+
+	defs := map[string]interface{}{}
+	result := b.hoist(schemaMap, "", defs)
+
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected root schema type after bundling")
+	}
+
+	if len(defs) > 0 {
+		resultMap["$defs"] = defs
+	}
+
+	if config.SplitOutput != "" {
+		if err := writeSplitOutput(config.SplitOutput, resultMap, defs); err != nil {
+			return nil, err
+		}
+	}
+
+	return resultMap, nil
+}
+
+// bundler tracks canonical-hash occurrence counts and assigned names
+// across the two passes performed by BundleSchema. hashNames maps a
+// subschema's canonicalHash to the def name it was first hoisted under,
+// so every occurrence of the same content - regardless of which ancestor
+// property reaches it - shares a single $defs entry.
+type bundler struct {
+	threshold int
+	counts    map[string]int
+	hashNames map[string]string
+}
+
+// count performs the first pass, recording how many times each
+// canonicalized object subschema occurs across the tree.
+func (b *bundler) count(node interface{}, ancestor string) {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if isObjectSchema(m) {
+		hash := canonicalHash(m)
+		b.counts[hash]++
+	}
+
+	if properties, ok := m["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(properties) {
+			b.count(properties[name], name)
+		}
+	}
+	if items, ok := m["items"].(map[string]interface{}); ok {
+		b.count(items, ancestor)
+	}
+}
+
+// demoteWrappers removes from b.counts any object schema whose only
+// content is properties that are themselves repeated (and thus will be
+// hoisted). Without this, a parent that merely wraps an already-repeated
+// child looks "repeated" too, purely because its sibling wraps the same
+// child — cascading hoists far past the innermost duplicate. Runs to a
+// fixed point so multiple wrapper levels are all demoted.
+func (b *bundler) demoteWrappers(node interface{}) {
+	for {
+		demoted := false
+		b.walkObjects(node, func(m map[string]interface{}) {
+			hash := canonicalHash(m)
+			if b.counts[hash] < b.threshold {
+				return
+			}
+			if b.isPureRefWrapper(m) {
+				delete(b.counts, hash)
+				demoted = true
+			}
+		})
+		if !demoted {
+			return
+		}
+	}
+}
+
+// isPureRefWrapper reports whether m's only content is "type"/"properties"
+// and every property value is itself a repeated (hoistable) subschema.
+func (b *bundler) isPureRefWrapper(m map[string]interface{}) bool {
+	properties, ok := m["properties"].(map[string]interface{})
+	if !ok || len(properties) == 0 {
+		return false
+	}
+
+	for k := range m {
+		if k != "type" && k != "properties" {
+			return false
+		}
+	}
+
+	for _, child := range properties {
+		childMap, ok := child.(map[string]interface{})
+		if !ok || !isObjectSchema(childMap) {
+			return false
+		}
+		if b.counts[canonicalHash(childMap)] < b.threshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+// walkObjects calls fn for every object-schema node in the tree.
+func (b *bundler) walkObjects(node interface{}, fn func(map[string]interface{})) {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if isObjectSchema(m) {
+		fn(m)
+	}
+
+	if properties, ok := m["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(properties) {
+			b.walkObjects(properties[name], fn)
+		}
+	}
+	if items, ok := m["items"].(map[string]interface{}); ok {
+		b.walkObjects(items, fn)
+	}
+}
+
+// sortedKeys returns m's keys sorted, so map iteration order never
+// influences which occurrence of a colliding def name wins.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hoist performs the second pass, rebuilding the tree and replacing any
+// subschema whose hash occurs at least b.threshold times with a $ref into
+// defs.
+func (b *bundler) hoist(node interface{}, ancestor string, defs map[string]interface{}) interface{} {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return node
+	}
+
+	rebuilt := map[string]interface{}{}
+	for k, v := range m {
+		switch k {
+		case "properties":
+			if properties, ok := v.(map[string]interface{}); ok {
+				newProperties := map[string]interface{}{}
+				for _, name := range sortedKeys(properties) {
+					newProperties[name] = b.hoistChild(properties[name], name, defs)
+				}
+				rebuilt[k] = newProperties
+				continue
+			}
+		case "items":
+			if items, ok := v.(map[string]interface{}); ok {
+				rebuilt[k] = b.hoistChild(items, ancestor, defs)
+				continue
+			}
+		}
+		rebuilt[k] = v
+	}
+
+	return rebuilt
+}
+
+// hoistChild hoists child (if eligible) into defs and returns either a
+// $ref map or the recursively-rebuilt child schema.
+func (b *bundler) hoistChild(child interface{}, ancestor string, defs map[string]interface{}) interface{} {
+	m, ok := child.(map[string]interface{})
+	if !ok || !isObjectSchema(m) {
+		return b.hoist(child, ancestor, defs)
+	}
+
+	hash := canonicalHash(m)
+	if b.counts[hash] < b.threshold {
+		return b.hoist(child, ancestor, defs)
+	}
+
+	// A subschema with this exact content may already have been hoisted
+	// via a different ancestor property; reuse that def instead of
+	// emitting a byte-identical duplicate under a new name.
+	if name, ok := b.hashNames[hash]; ok {
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	}
+
+	rebuilt := b.hoist(child, ancestor, defs)
+
+	name := b.defName(ancestor, hash, defs)
+	b.hashNames[hash] = name
+	defs[name] = rebuilt
+
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+// defName picks a name for a newly-hoisted def, preferring the nearest
+// ancestor property name and falling back to a content hash suffix if
+// that name is already taken by an unrelated (different-hash) def.
+// Callers only reach defName for a hash with no existing entry in
+// b.hashNames, so any collision here is against different content.
+func (b *bundler) defName(ancestor, hash string, defs map[string]interface{}) string {
+	base := exportedIdentifier(ancestor)
+	if base == "" {
+		base = "Def"
+	}
+	base = string(lowerFirst(base))
+
+	if _, ok := defs[base]; ok {
+		return base + "-" + hash[:8]
+	}
+
+	return base
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return string(s[0]|0x20) + s[1:]
+}
+
+func isObjectSchema(m map[string]interface{}) bool {
+	t, _ := m["type"].(string)
+	_, hasProperties := m["properties"]
+	return t == "object" && hasProperties
+}
+
+// canonicalHash returns a stable content hash for a subschema, so
+// structurally identical subschemas compare equal regardless of map key
+// ordering.
+func canonicalHash(m map[string]interface{}) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ordered := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		ordered = append(ordered, k, m[k])
+	}
+
+	// json.Marshal on a slice preserves this order, giving a canonical
+	// byte representation to hash regardless of the source map's order.
+	b, err := json.Marshal(ordered)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeSplitOutput writes each def to its own file under dir, rewriting
+// internal "#/$defs/<name>" references to external "<name>.json" URIs,
+// plus an index schema that $refs them all.
+func writeSplitOutput(dir string, root map[string]interface{}, defs map[string]interface{}) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating split output directory: %w", err)
+	}
+
+	for name, def := range defs {
+		externalized := externalizeRefs(def)
+
+		b, err := json.MarshalIndent(externalized, "", "  ")
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), b, 0644); err != nil {
+			return fmt.Errorf("error writing def %q: %w", name, err)
+		}
+	}
+
+	index := externalizeRefs(root)
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), b, 0644); err != nil {
+		return fmt.Errorf("error writing index schema: %w", err)
+	}
+
+	return nil
+}
+
+// externalizeRefs rewrites internal "#/$defs/<name>" $refs into external
+// "<name>.json" URIs suitable for -split-output.
+func externalizeRefs(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		rebuilt := map[string]interface{}{}
+		for k, val := range v {
+			if k == "$ref" {
+				if ref, ok := val.(string); ok {
+					rebuilt[k] = refTypeName(ref) + ".json"
+					continue
+				}
+			}
+			rebuilt[k] = externalizeRefs(val)
+		}
+		return rebuilt
+	default:
+		return node
+	}
+}
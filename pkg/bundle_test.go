@@ -0,0 +1,141 @@
+package pkg
+
+import "testing"
+
+func TestCanonicalHashIgnoresKeyOrder(t *testing.T) {
+	a := map[string]interface{}{"type": "string", "title": "Name"}
+	b := map[string]interface{}{"title": "Name", "type": "string"}
+
+	if canonicalHash(a) != canonicalHash(b) {
+		t.Fatalf("canonicalHash should be order-independent: %q != %q", canonicalHash(a), canonicalHash(b))
+	}
+}
+
+func TestCanonicalHashDiffersOnContent(t *testing.T) {
+	a := map[string]interface{}{"type": "string"}
+	b := map[string]interface{}{"type": "integer"}
+
+	if canonicalHash(a) == canonicalHash(b) {
+		t.Fatal("canonicalHash should differ for different content")
+	}
+}
+
+func repeatedLabelsSchema() map[string]interface{} {
+	labels := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"key":   map[string]interface{}{"type": "string"},
+			"value": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"podLabels": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"labels": labels},
+			},
+			"serviceLabels": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"labels": labels},
+			},
+		},
+	}
+}
+
+func TestBundleSchemaHoistsRepeatedSubschema(t *testing.T) {
+	config := &Config{BundleThreshold: 2}
+
+	result, err := BundleSchema(config, repeatedLabelsSchema())
+	if err != nil {
+		t.Fatalf("BundleSchema returned error: %v", err)
+	}
+
+	defs, ok := result["$defs"].(map[string]interface{})
+	if !ok || len(defs) != 1 {
+		t.Fatalf("expected exactly one hoisted def, got %#v", result["$defs"])
+	}
+
+	properties := result["properties"].(map[string]interface{})
+	for _, parent := range []string{"podLabels", "serviceLabels"} {
+		parentSchema := properties[parent].(map[string]interface{})
+		parentProps := parentSchema["properties"].(map[string]interface{})
+		labelsRef, ok := parentProps["labels"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s.labels was not rebuilt as a map", parent)
+		}
+		if _, ok := labelsRef["$ref"]; !ok {
+			t.Errorf("%s.labels should have been replaced with a $ref, got %#v", parent, labelsRef)
+		}
+	}
+}
+
+func TestBundleSchemaBelowThresholdLeavesSchemaUnchanged(t *testing.T) {
+	config := &Config{BundleThreshold: 3}
+
+	result, err := BundleSchema(config, repeatedLabelsSchema())
+	if err != nil {
+		t.Fatalf("BundleSchema returned error: %v", err)
+	}
+
+	if _, ok := result["$defs"]; ok {
+		t.Errorf("expected no $defs below the occurrence threshold, got %#v", result["$defs"])
+	}
+}
+
+func TestBundleSchemaDedupsIdenticalSubschemasAcrossAncestors(t *testing.T) {
+	resources := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"cpu":    map[string]interface{}{"type": "string"},
+			"memory": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	schemaMap := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"webResources":    resources,
+			"workerResources": resources,
+		},
+	}
+
+	config := &Config{BundleThreshold: 2}
+
+	result, err := BundleSchema(config, schemaMap)
+	if err != nil {
+		t.Fatalf("BundleSchema returned error: %v", err)
+	}
+
+	defs, ok := result["$defs"].(map[string]interface{})
+	if !ok || len(defs) != 1 {
+		t.Fatalf("expected identical subschemas under different ancestors to share one def, got %#v", result["$defs"])
+	}
+
+	properties := result["properties"].(map[string]interface{})
+	webRef := properties["webResources"].(map[string]interface{})["$ref"]
+	workerRef := properties["workerResources"].(map[string]interface{})["$ref"]
+	if webRef == nil || workerRef == nil {
+		t.Fatalf("expected both properties to be replaced with $refs, got web=%#v worker=%#v", properties["webResources"], properties["workerResources"])
+	}
+	if webRef != workerRef {
+		t.Errorf("expected webResources and workerResources to $ref the same def, got %v and %v", webRef, workerRef)
+	}
+}
+
+func TestBundlerDefNameCollisionFallsBackToHashSuffix(t *testing.T) {
+	b := &bundler{threshold: 2, counts: map[string]int{}, hashNames: map[string]string{}}
+
+	defs := map[string]interface{}{}
+	first := map[string]interface{}{"type": "string"}
+	second := map[string]interface{}{"type": "integer"}
+
+	firstName := b.defName("labels", canonicalHash(first), defs)
+	defs[firstName] = first
+
+	secondName := b.defName("labels", canonicalHash(second), defs)
+	if secondName == firstName {
+		t.Fatalf("expected distinct names for colliding ancestor %q with different content, got %q twice", "labels", firstName)
+	}
+}
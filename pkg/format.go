@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// FormatChecker validates that a decoded JSON value satisfies a named
+// "format" keyword. It mirrors jsonschema.Formats' checker signature so
+// registered formats can be plugged straight into the validator.
+type FormatChecker func(v interface{}) bool
+
+func init() {
+	RegisterFormat("duration", durationFormatChecker)
+	RegisterFormat("k8s-quantity", k8sQuantityFormatChecker)
+	RegisterFormat("k8s-name", k8sNameFormatChecker)
+	RegisterFormat("port", portFormatChecker)
+	RegisterFormat("cron", cronFormatChecker)
+	RegisterFormat("semver", semverFormatChecker)
+	RegisterFormat("image-reference", imageReferenceFormatChecker)
+}
+
+// RegisterFormat registers a FormatChecker under the given name so that
+// the validate subcommand enforces it wherever the schema carries that
+// format (e.g. from a `# @schema format=<name>` annotation), without
+// requiring changes to this module. The annotation itself accepts any
+// format name, registered or not.
+func RegisterFormat(name string, checker FormatChecker) {
+	jsonschema.Formats[name] = checker
+}
+
+func durationFormatChecker(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
+
+// k8sQuantityPattern mirrors the grammar accepted by resource.Quantity: a
+// signed decimal number followed by an optional Kubernetes suffix, either
+// binary SI ("Ki", "Mi", "Gi", "Ti", "Pi", "Ei") or decimal SI ("n", "u",
+// "m", "k", "M", "G", "T", "P", "E" — note the lowercase "k", which is
+// Kubernetes' own convention and not standard SI).
+var k8sQuantityPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d+)?|\.\d+)(([eE][+-]?\d+)|Ki|Mi|Gi|Ti|Pi|Ei|[numkMGTPE])?$`)
+
+func k8sQuantityFormatChecker(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return k8sQuantityPattern.MatchString(s)
+}
+
+// dns1123SubdomainPattern implements the DNS-1123 subdomain rules used
+// throughout Kubernetes object names.
+var dns1123SubdomainPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+func k8sNameFormatChecker(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return len(s) <= 253 && dns1123SubdomainPattern.MatchString(s)
+}
+
+func portFormatChecker(v interface{}) bool {
+	switch p := v.(type) {
+	case float64:
+		return p >= 1 && p <= 65535 && p == float64(int(p))
+	case string:
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return false
+		}
+		return n >= 1 && n <= 65535
+	default:
+		return true
+	}
+}
+
+// cronFieldRanges holds the minimum and maximum values cron allows for
+// each of the 5 fields, in order: minute, hour, day-of-month, month, and
+// day-of-week.
+var cronFieldRanges = [5][2]int{
+	{0, 59},
+	{0, 23},
+	{1, 31},
+	{1, 12},
+	{0, 7},
+}
+
+// cronValuePattern matches a single comma-separated element of a cron
+// field: a "*" wildcard or a number (optionally a "-" range), with an
+// optional "/" step.
+var cronValuePattern = regexp.MustCompile(`^(\*|[0-9]+(-[0-9]+)?)(/[0-9]+)?$`)
+
+func cronFormatChecker(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return false
+	}
+
+	for i, field := range fields {
+		min, max := cronFieldRanges[i][0], cronFieldRanges[i][1]
+		for _, value := range strings.Split(field, ",") {
+			if !cronValueInRange(value, min, max) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// cronValueInRange reports whether value (a single "*", number, or
+// "n-m" range, optionally followed by "/step") is syntactically valid
+// and, for numbers, falls within [min, max].
+func cronValueInRange(value string, min, max int) bool {
+	m := cronValuePattern.FindStringSubmatch(value)
+	if m == nil {
+		return false
+	}
+	if m[1] == "*" {
+		return true
+	}
+
+	for _, bound := range strings.SplitN(m[1], "-", 2) {
+		n, err := strconv.Atoi(bound)
+		if err != nil || n < min || n > max {
+			return false
+		}
+	}
+
+	return true
+}
+
+// semverPattern is the regular expression published at semver.org, extended
+// to accept an optional leading "v" (e.g. "v1.2.3").
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func semverFormatChecker(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return semverPattern.MatchString(s)
+}
+
+// imageReferencePattern is a pragmatic approximation of the OCI/Docker
+// image reference grammar: an optional registry host (which may itself
+// carry a ":<port>", e.g. "registry.internal:5000"), a repository path,
+// an image name, and an optional tag or digest.
+var imageReferencePattern = regexp.MustCompile(`^([a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)*(:[0-9]+)?/)?[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*(/[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*)*(:[a-zA-Z0-9_.-]+)?(@[a-zA-Z0-9]+:[a-fA-F0-9]+)?$`)
+
+func imageReferenceFormatChecker(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	if s == "" {
+		return false
+	}
+	return imageReferencePattern.MatchString(s)
+}
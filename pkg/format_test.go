@@ -0,0 +1,116 @@
+package pkg
+
+import "testing"
+
+func TestDurationFormatChecker(t *testing.T) {
+	cases := map[string]bool{
+		"5s":     true,
+		"10m30s": true,
+		"1h":     true,
+		"5":      false,
+		"soon":   false,
+	}
+	for in, want := range cases {
+		if got := durationFormatChecker(in); got != want {
+			t.Errorf("durationFormatChecker(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestK8sQuantityFormatChecker(t *testing.T) {
+	cases := map[string]bool{
+		"500m":  true,
+		"2Gi":   true,
+		"128Ki": true,
+		"1":     true,
+		"1.5G":  true,
+		"128ki": false,
+		"2gi":   false,
+		"foo":   false,
+	}
+	for in, want := range cases {
+		if got := k8sQuantityFormatChecker(in); got != want {
+			t.Errorf("k8sQuantityFormatChecker(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestK8sNameFormatChecker(t *testing.T) {
+	cases := map[string]bool{
+		"my-app":       true,
+		"my-app.local": true,
+		"MyApp":        false,
+		"-my-app":      false,
+		"":             false,
+	}
+	for in, want := range cases {
+		if got := k8sNameFormatChecker(in); got != want {
+			t.Errorf("k8sNameFormatChecker(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestPortFormatChecker(t *testing.T) {
+	cases := map[interface{}]bool{
+		float64(80):    true,
+		float64(65535): true,
+		float64(0):     false,
+		float64(65536): false,
+		"8080":         true,
+		"not-a-port":   false,
+	}
+	for in, want := range cases {
+		if got := portFormatChecker(in); got != want {
+			t.Errorf("portFormatChecker(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestCronFormatChecker(t *testing.T) {
+	cases := map[string]bool{
+		"* * * * *":         true,
+		"0 0 1 1 *":         true,
+		"*/5 0,12 1-15 * *": true,
+		"not a cron at all": false,
+		"99 99 99 99 99":    false,
+		"* * * *":           false,
+		"* * * * * *":       false,
+	}
+	for in, want := range cases {
+		if got := cronFormatChecker(in); got != want {
+			t.Errorf("cronFormatChecker(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestSemverFormatChecker(t *testing.T) {
+	cases := map[string]bool{
+		"1.2.3":        true,
+		"v1.2.3":       true,
+		"1.2.3-rc.1":   true,
+		"1.2":          false,
+		"not-a-semver": false,
+	}
+	for in, want := range cases {
+		if got := semverFormatChecker(in); got != want {
+			t.Errorf("semverFormatChecker(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestImageReferenceFormatChecker(t *testing.T) {
+	cases := map[string]bool{
+		"nginx":                                 true,
+		"nginx:1.21":                            true,
+		"library/nginx:1.21":                    true,
+		"registry.internal:5000/myapp:1.0":      true,
+		"registry.internal:5000/team/myapp:1.0": true,
+		"":                                      false,
+		"Nginx":                                 false,
+	}
+	for in, want := range cases {
+		if got := imageReferenceFormatChecker(in); got != want {
+			t.Errorf("imageReferenceFormatChecker(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strings"
 
@@ -39,14 +37,14 @@ func GenerateJsonSchema(config *Config) error {
 
 	// Iterate over the input YAML files
 	for _, filePath := range config.Input {
-		content, err := getFileContent(filePath)
+		content, format, err := getFileContent(filePath)
 		if err != nil {
 			return errors.New("error reading YAML file(s)")
 		}
 
-		var node yaml.Node
-		if err := yaml.Unmarshal(content, &node); err != nil {
-			return errors.New("error unmarshaling YAML")
+		node, err := parseContent(content, format)
+		if err != nil {
+			return err
 		}
 
 		if len(node.Content) == 0 {
@@ -133,28 +131,18 @@ func setAdditionalProperties(s *Schema, value bool) {
 	}
 }
 
-func getFileContent(filePath string) ([]byte, error) {
-	if isURL(filePath) {
-		return downloadFile(filePath)
-	} else {
-		return os.ReadFile(filePath)
-	}
-}
-
-func isURL(path string) bool {
-	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
-}
-
-func downloadFile(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to download file: %s", resp.Status)
+// parseContent unmarshals raw input content into a YAML document node,
+// dispatching on the format hint resolved by getFileContent. JSON is
+// parsed via yaml.Unmarshal, which accepts JSON as a subset of YAML.
+func parseContent(content []byte, format string) (*yaml.Node, error) {
+	switch format {
+	case "yaml", "json", "":
+		var node yaml.Node
+		if err := yaml.Unmarshal(content, &node); err != nil {
+			return nil, errors.New("error unmarshaling YAML")
+		}
+		return &node, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (supported: yaml, json)", format)
 	}
-
-	return io.ReadAll(resp.Body)
 }
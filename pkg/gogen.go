@@ -0,0 +1,530 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+)
+
+// goStruct is an intermediate representation of a single generated struct,
+// built up while walking the JSON schema tree.
+type goStruct struct {
+	Name    string
+	Comment string
+	Fields  []goField
+}
+
+// goField is a single field of a goStruct.
+type goField struct {
+	Name     string
+	JSONName string
+	GoType   string
+	Required bool
+	Comment  string
+
+	// Union is the bare (non-pointer) type name of the oneOf/anyOf
+	// interface this field decodes to, or "" if the field is not a
+	// union. Set so the containing struct can generate a custom
+	// UnmarshalJSON that routes this field through Unmarshal<Union>.
+	Union string
+}
+
+// goEnum is a set of typed string constants generated for a schema
+// property that declares an `enum`.
+type goEnum struct {
+	TypeName string
+	Values   []string
+}
+
+// goInterface is generated for a schema node that declares `oneOf` or
+// `anyOf`: a sealed interface implemented by each struct variant, so a
+// value that could take several shapes is still represented with
+// compile-time safety rather than collapsing to `interface{}`. Alongside
+// the marker interface, render emits an Unmarshal<Name> package function
+// that tries each struct variant in turn, and any struct with a field of
+// this type gets a custom UnmarshalJSON that routes through it -
+// encoding/json can't decode into an interface-typed field on its own.
+type goInterface struct {
+	Name     string
+	Variants []string
+}
+
+// GenerateGoStructs walks the JSON schema produced by convertSchemaToMap
+// and writes a Go source file of typed structs to config.GoOutput, so
+// operators, admission webhooks, and tests can consume Helm values with
+// compile-time safety derived from the same schema.
+func GenerateGoStructs(config *Config, schemaMap map[string]interface{}) error {
+	if config.GoOutput == "" {
+		return errors.New("go-output flag is required")
+	}
+	if config.GoPackage == "" {
+		return errors.New("go-package flag is required")
+	}
+
+	g := &goGenerator{
+		config:     config,
+		names:      map[string]int{},
+		defs:       map[string]map[string]interface{}{},
+		defTypes:   map[string]string{},
+		resolving:  map[string]bool{},
+		unionNames: map[string]bool{},
+	}
+
+	if defs, ok := schemaMap["$defs"].(map[string]interface{}); ok {
+		for name, def := range defs {
+			if defSchema, ok := def.(map[string]interface{}); ok {
+				g.defs[name] = defSchema
+			}
+		}
+	}
+
+	rootName := "Values"
+	if config.GoTypeNameFromTitle {
+		if title, ok := schemaMap["title"].(string); ok && title != "" {
+			rootName = g.uniqueName(exportedIdentifier(title))
+		}
+	}
+
+	if _, err := g.walk(rootName, schemaMap); err != nil {
+		return err
+	}
+
+	src := g.render()
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("error formatting generated Go source: %w", err)
+	}
+
+	if err := os.WriteFile(config.GoOutput, formatted, 0644); err != nil {
+		return errors.New("error writing generated Go source to file")
+	}
+
+	fmt.Println("Go structs successfully generated")
+
+	return nil
+}
+
+// goGenerator accumulates structs and enums while walking a schema tree.
+type goGenerator struct {
+	config     *Config
+	names      map[string]int
+	structs    []*goStruct
+	enums      []*goEnum
+	interfaces []*goInterface
+
+	// defs holds the raw $defs subschemas from the bundled schema (see
+	// chunk0-5), keyed by def name. defTypes memoizes the Go type name
+	// each def resolves to, and resolving guards against cyclic $refs.
+	defs      map[string]map[string]interface{}
+	defTypes  map[string]string
+	resolving map[string]bool
+
+	// unionNames records every type name walkUnion has generated, so
+	// walkObject can tell a union-typed field apart from an ordinary
+	// struct/map field and route it through a generated UnmarshalJSON.
+	unionNames map[string]bool
+}
+
+// walk returns the Go type name that represents the given schema node,
+// registering a struct, enum, or interface for it as needed.
+func (g *goGenerator) walk(name string, schema map[string]interface{}) (string, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		return g.resolveRef(ref)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		return g.walkEnum(name, enum), nil
+	}
+
+	if variants, ok := schema["oneOf"].([]interface{}); ok {
+		return g.walkUnion(name, variants)
+	}
+	if variants, ok := schema["anyOf"].([]interface{}); ok {
+		return g.walkUnion(name, variants)
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return g.walkObjectOrMap(name, schema)
+	case "array":
+		return g.walkArray(name, schema)
+	case "string":
+		return "string", nil
+	case "integer":
+		return "int64", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	default:
+		return "interface{}", nil
+	}
+}
+
+// resolveRef resolves a "#/$defs/<name>" reference to the Go type
+// generated for that definition, generating it on first use and reusing
+// the same type for every subsequent reference. This also covers schemas
+// produced by chunk0-5's bundling, which hoists repeated subschemas into
+// $defs and replaces them with $refs.
+func (g *goGenerator) resolveRef(ref string) (string, error) {
+	defName := refTypeName(ref)
+
+	if typeName, ok := g.defTypes[defName]; ok {
+		return typeName, nil
+	}
+
+	defSchema, ok := g.defs[defName]
+	if !ok {
+		return "", fmt.Errorf("unresolved $ref %q: %q not found in $defs", ref, defName)
+	}
+	if g.resolving[defName] {
+		return "", fmt.Errorf("circular $ref detected while resolving %q", ref)
+	}
+
+	g.resolving[defName] = true
+	typeName, err := g.walk(defName, defSchema)
+	delete(g.resolving, defName)
+	if err != nil {
+		return "", err
+	}
+
+	g.defTypes[defName] = typeName
+
+	return typeName, nil
+}
+
+// walkObjectOrMap treats an object schema with no declared properties as
+// a free-form map (common for Helm values like `nodeSelector` and
+// `annotations`), typed by `additionalProperties` when it is itself a
+// schema and map[string]interface{} otherwise. An object schema with
+// declared properties becomes a real struct.
+func (g *goGenerator) walkObjectOrMap(name string, schema map[string]interface{}) (string, error) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) > 0 {
+		return g.walkObject(name, schema)
+	}
+
+	if apSchema, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		valueType, err := g.walk(name+"Value", apSchema)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + valueType, nil
+	}
+
+	return "map[string]interface{}", nil
+}
+
+func (g *goGenerator) walkObject(name string, schema map[string]interface{}) (string, error) {
+	typeName := g.uniqueName(exportedIdentifier(name))
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if req, ok := schema["required"].([]string); ok {
+		for _, r := range req {
+			required[r] = true
+		}
+	}
+
+	propNames := make([]string, 0, len(properties))
+	for propName := range properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	s := &goStruct{Name: typeName}
+	if title, ok := schema["title"].(string); ok && title != "" {
+		s.Comment = title
+	}
+
+	for _, propName := range propNames {
+		propSchema, ok := properties[propName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		fieldTypeName, err := g.walk(typeName+"_"+exportedIdentifier(propName), propSchema)
+		if err != nil {
+			return "", err
+		}
+
+		union := ""
+		if g.unionNames[fieldTypeName] {
+			union = fieldTypeName
+		}
+
+		isRequired := required[propName]
+		if !isRequired && union == "" {
+			fieldTypeName = pointerize(fieldTypeName)
+		}
+
+		field := goField{
+			Name:     exportedIdentifier(propName),
+			JSONName: propName,
+			GoType:   fieldTypeName,
+			Required: isRequired,
+			Union:    union,
+		}
+		if desc, ok := propSchema["description"].(string); ok {
+			field.Comment = desc
+		}
+		s.Fields = append(s.Fields, field)
+	}
+
+	g.structs = append(g.structs, s)
+
+	return typeName, nil
+}
+
+func (g *goGenerator) walkArray(name string, schema map[string]interface{}) (string, error) {
+	items, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		return "[]interface{}", nil
+	}
+
+	itemType, err := g.walk(name+"Item", items)
+	if err != nil {
+		return "", err
+	}
+
+	return "[]" + itemType, nil
+}
+
+func (g *goGenerator) walkEnum(name string, values []interface{}) string {
+	typeName := g.uniqueName(exportedIdentifier(name))
+
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	g.enums = append(g.enums, &goEnum{TypeName: typeName, Values: strs})
+
+	return typeName
+}
+
+// walkUnion generates a sealed interface for a `oneOf`/`anyOf` schema
+// node: one marker method per variant struct, so each variant satisfies
+// the interface and non-struct variants (which can't carry a marker
+// method) are still listed in the interface's doc comment rather than
+// silently discarded.
+func (g *goGenerator) walkUnion(name string, variants []interface{}) (string, error) {
+	typeName := g.uniqueName(exportedIdentifier(name))
+	g.unionNames[typeName] = true
+	iface := &goInterface{Name: typeName}
+
+	for i, variant := range variants {
+		variantSchema, ok := variant.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		variantType, err := g.walk(fmt.Sprintf("%s%d", name, i+1), variantSchema)
+		if err != nil {
+			return "", err
+		}
+
+		iface.Variants = append(iface.Variants, variantType)
+	}
+
+	g.interfaces = append(g.interfaces, iface)
+
+	return typeName, nil
+}
+
+// uniqueName appends a numeric suffix to name if it collides with a
+// previously generated type, so every generated identifier is distinct.
+func (g *goGenerator) uniqueName(name string) string {
+	if name == "" {
+		name = "Type"
+	}
+	count := g.names[name]
+	g.names[name] = count + 1
+	if count == 0 {
+		return name
+	}
+	candidate := fmt.Sprintf("%s%d", name, count)
+	for g.names[candidate] > 0 {
+		count++
+		candidate = fmt.Sprintf("%s%d", name, count)
+	}
+	g.names[name] = count + 1
+	g.names[candidate] = 1
+	return candidate
+}
+
+// render assembles the full Go source file from the accumulated structs,
+// enums, and interfaces.
+func (g *goGenerator) render() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "package %s\n\n", g.config.GoPackage)
+
+	if len(g.interfaces) > 0 {
+		b.WriteString("import (\n\t\"encoding/json\"\n\t\"fmt\"\n)\n\n")
+	}
+
+	for _, e := range g.enums {
+		fmt.Fprintf(&b, "type %s string\n\n", e.TypeName)
+		if len(e.Values) > 0 {
+			b.WriteString("const (\n")
+			for _, v := range e.Values {
+				fmt.Fprintf(&b, "\t%s%s %s = %q\n", e.TypeName, exportedIdentifier(v), e.TypeName, v)
+			}
+			b.WriteString(")\n\n")
+		}
+	}
+
+	structNames := map[string]bool{}
+	for _, s := range g.structs {
+		structNames[s.Name] = true
+	}
+
+	for _, iface := range g.interfaces {
+		fmt.Fprintf(&b, "// %s is satisfied by exactly one of: %s.\n", iface.Name, strings.Join(iface.Variants, ", "))
+		fmt.Fprintf(&b, "type %s interface {\n\tis%s()\n}\n\n", iface.Name, iface.Name)
+
+		var structVariants []string
+		for _, variant := range iface.Variants {
+			if !structNames[variant] {
+				// Non-struct variants (primitives, maps, slices) can't
+				// carry a marker method; they're listed in the doc
+				// comment above, but a value of the interface type
+				// never holds them.
+				continue
+			}
+			fmt.Fprintf(&b, "func (%s) is%s() {}\n\n", variant, iface.Name)
+			structVariants = append(structVariants, variant)
+		}
+
+		fmt.Fprintf(&b, "// Unmarshal%s tries each variant of %s in turn and returns the\n", iface.Name, iface.Name)
+		fmt.Fprintf(&b, "// first one data unmarshals into cleanly.\n")
+		fmt.Fprintf(&b, "func Unmarshal%s(data []byte) (%s, error) {\n", iface.Name, iface.Name)
+		var lastErr string
+		for i, variant := range structVariants {
+			errVar := fmt.Sprintf("err%d", i)
+			fmt.Fprintf(&b, "\tvar v%d %s\n", i, variant)
+			fmt.Fprintf(&b, "\t%s := json.Unmarshal(data, &v%d)\n", errVar, i)
+			fmt.Fprintf(&b, "\tif %s == nil {\n\t\treturn v%d, nil\n\t}\n", errVar, i)
+			lastErr = errVar
+		}
+		if len(structVariants) > 0 {
+			fmt.Fprintf(&b, "\treturn nil, fmt.Errorf(\"no variant of %s matched: %%w\", %s)\n", iface.Name, lastErr)
+		} else {
+			fmt.Fprintf(&b, "\treturn nil, fmt.Errorf(\"no struct variant of %s available to unmarshal into\")\n", iface.Name)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, s := range g.structs {
+		if s.Comment != "" {
+			fmt.Fprintf(&b, "// %s %s\n", s.Name, s.Comment)
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", s.Name)
+		for _, f := range s.Fields {
+			if f.Comment != "" {
+				fmt.Fprintf(&b, "\t// %s\n", f.Comment)
+			}
+			omitempty := ""
+			if !f.Required {
+				omitempty = ",omitempty"
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s%s\" yaml:\"%s%s\"`\n", f.Name, f.GoType, f.JSONName, omitempty, f.JSONName, omitempty)
+		}
+		b.WriteString("}\n\n")
+
+		g.renderUnionUnmarshal(&b, s)
+	}
+
+	return b.String()
+}
+
+// renderUnionUnmarshal emits a custom UnmarshalJSON for s if any of its
+// fields hold a oneOf/anyOf interface. encoding/json can't populate an
+// interface-typed field on its own, so the other fields are decoded
+// normally via a type alias and each union field is captured as raw JSON
+// and routed through the matching Unmarshal<Union> helper.
+func (g *goGenerator) renderUnionUnmarshal(b *strings.Builder, s *goStruct) {
+	var unionFields []goField
+	for _, f := range s.Fields {
+		if f.Union != "" {
+			unionFields = append(unionFields, f)
+		}
+	}
+	if len(unionFields) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "func (s *%s) UnmarshalJSON(data []byte) error {\n", s.Name)
+	fmt.Fprintf(b, "\ttype alias %s\n", s.Name)
+	b.WriteString("\taux := struct {\n")
+	for _, f := range unionFields {
+		fmt.Fprintf(b, "\t\t%s json.RawMessage `json:\"%s,omitempty\"`\n", f.Name, f.JSONName)
+	}
+	b.WriteString("\t\t*alias\n")
+	b.WriteString("\t}{alias: (*alias)(s)}\n\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &aux); err != nil {\n\t\treturn err\n\t}\n\n")
+
+	for _, f := range unionFields {
+		fmt.Fprintf(b, "\tif len(aux.%s) > 0 {\n", f.Name)
+		fmt.Fprintf(b, "\t\tv, err := Unmarshal%s(aux.%s)\n", f.Union, f.Name)
+		b.WriteString("\t\tif err != nil {\n")
+		fmt.Fprintf(b, "\t\t\treturn fmt.Errorf(\"field %s: %%w\", err)\n", f.JSONName)
+		b.WriteString("\t\t}\n")
+		fmt.Fprintf(b, "\t\ts.%s = v\n", f.Name)
+		b.WriteString("\t}\n\n")
+	}
+
+	b.WriteString("\treturn nil\n}\n\n")
+}
+
+// schemaType returns the schema's "type" as a string, defaulting to
+// "object" when absent, mirroring the leniency of the rest of the schema
+// walking code.
+func schemaType(schema map[string]interface{}) string {
+	if t, ok := schema["type"].(string); ok {
+		return t
+	}
+	return "object"
+}
+
+func refTypeName(ref string) string {
+	parts := strings.Split(ref, "/")
+	return parts[len(parts)-1]
+}
+
+func pointerize(goType string) string {
+	if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") {
+		return goType
+	}
+	return "*" + goType
+}
+
+// exportedIdentifier converts an arbitrary schema property name or title
+// into an exported Go identifier, e.g. "image-pull-policy" -> "ImagePullPolicy".
+func exportedIdentifier(name string) string {
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	})
+
+	var b strings.Builder
+	for _, f := range fields {
+		if f == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(f[:1]))
+		b.WriteString(f[1:])
+	}
+
+	if b.Len() == 0 {
+		return "Field"
+	}
+
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportedIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"image-pull-policy": "ImagePullPolicy",
+		"node_selector":     "NodeSelector",
+		"service.account":   "ServiceAccount",
+		"replica count":     "ReplicaCount",
+		"":                  "Field",
+	}
+	for in, want := range cases {
+		if got := exportedIdentifier(in); got != want {
+			t.Errorf("exportedIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPointerize(t *testing.T) {
+	cases := map[string]string{
+		"string":            "*string",
+		"[]string":          "[]string",
+		"map[string]string": "map[string]string",
+		"MyStruct":          "*MyStruct",
+	}
+	for in, want := range cases {
+		if got := pointerize(in); got != want {
+			t.Errorf("pointerize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRefTypeName(t *testing.T) {
+	if got := refTypeName("#/$defs/Labels"); got != "Labels" {
+		t.Errorf("refTypeName(...) = %q, want %q", got, "Labels")
+	}
+}
+
+func TestUniqueNameAppendsSuffixOnCollision(t *testing.T) {
+	g := &goGenerator{names: map[string]int{}}
+
+	first := g.uniqueName("Values")
+	second := g.uniqueName("Values")
+
+	if first == second {
+		t.Fatalf("expected distinct names, got %q twice", first)
+	}
+	if first != "Values" {
+		t.Errorf("first occurrence should keep the plain name, got %q", first)
+	}
+}
+
+func TestGenerateGoStructsRequiresOutputAndPackage(t *testing.T) {
+	if err := GenerateGoStructs(&Config{}, map[string]interface{}{}); err == nil {
+		t.Fatal("expected error when go-output is missing")
+	}
+	if err := GenerateGoStructs(&Config{GoOutput: "out.go"}, map[string]interface{}{}); err == nil {
+		t.Fatal("expected error when go-package is missing")
+	}
+}
+
+func TestGenerateGoStructsEmitsValidSyntax(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":  "object",
+		"title": "Values",
+		"properties": map[string]interface{}{
+			"replicaCount": map[string]interface{}{"type": "integer"},
+			"nodeSelector": map[string]interface{}{"type": "object"},
+			"image": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tag": map[string]interface{}{"type": "string"},
+				},
+			},
+			"pullPolicy": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"Always", "IfNotPresent"},
+			},
+			"probe": map[string]interface{}{
+				"oneOf": []interface{}{
+					map[string]interface{}{
+						"type":       "object",
+						"title":      "HttpProbe",
+						"properties": map[string]interface{}{"path": map[string]interface{}{"type": "string"}},
+					},
+					map[string]interface{}{
+						"type":  "object",
+						"title": "ExecProbe",
+						"properties": map[string]interface{}{
+							"command": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "generated.go")
+
+	config := &Config{GoOutput: out, GoPackage: "generated"}
+	if err := GenerateGoStructs(config, schema); err != nil {
+		t.Fatalf("GenerateGoStructs returned error: %v", err)
+	}
+
+	src, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), out, src, parser.AllErrors); err != nil {
+		t.Fatalf("generated source is not valid Go: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		"type Values struct {",
+		"ReplicaCount *int64",
+		"NodeSelector map[string]interface{}",
+		"type ValuesPullPolicy string",
+		"ValuesPullPolicyAlways       ValuesPullPolicy",
+		"type ValuesProbe interface {",
+		"func UnmarshalValuesProbe(data []byte) (ValuesProbe, error)",
+		"func (s *Values) UnmarshalJSON(data []byte) error",
+		"s.Probe = v",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source missing %q:\n%s", want, src)
+		}
+	}
+
+	// An interface is already nilable, so an optional oneOf/anyOf field
+	// should stay a bare interface type rather than a pointer to one.
+	if strings.Contains(string(src), "*ValuesProbe") {
+		t.Errorf("generated source should not pointerize the union field Probe:\n%s", src)
+	}
+}
+
+func TestWalkUnionRegistersUnionAndListsAllVariants(t *testing.T) {
+	g := &goGenerator{names: map[string]int{}, unionNames: map[string]bool{}}
+
+	typeName, err := g.walkUnion("Values_probe", []interface{}{
+		map[string]interface{}{"type": "string"},
+		map[string]interface{}{"type": "object", "properties": map[string]interface{}{"path": map[string]interface{}{"type": "string"}}},
+	})
+	if err != nil {
+		t.Fatalf("walkUnion returned error: %v", err)
+	}
+	if !g.unionNames[typeName] {
+		t.Errorf("expected %q to be registered as a union type", typeName)
+	}
+	if len(g.interfaces) != 1 || len(g.interfaces[0].Variants) != 2 {
+		t.Fatalf("expected both variants listed, got %#v", g.interfaces)
+	}
+}
@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Loader fetches the raw content behind a URL for a given input scheme.
+type Loader interface {
+	Load(u *url.URL) ([]byte, error)
+}
+
+// loaders holds the registered Loader implementations, keyed by URL scheme.
+var loaders = map[string]Loader{}
+
+func init() {
+	RegisterLoader("file", fileLoader{})
+	RegisterLoader("http", httpLoader{})
+	RegisterLoader("https", httpLoader{})
+	RegisterLoader("stdin", stdinLoader{})
+}
+
+// RegisterLoader registers a Loader for the given URL scheme, overwriting
+// any loader previously registered for that scheme. This allows downstream
+// code to plug in additional schemes (e.g. oci://, helm://) without forking.
+func RegisterLoader(scheme string, l Loader) {
+	loaders[scheme] = l
+}
+
+// getFileContent resolves an input entry to its raw content and a format
+// hint. Entries without a "scheme://" prefix are treated as plain file
+// paths for backwards compatibility. The format hint is taken from the
+// "format" query parameter when present, and falls back to the entry's
+// file extension otherwise.
+func getFileContent(input string) ([]byte, string, error) {
+	if !strings.Contains(input, "://") {
+		content, err := os.ReadFile(input)
+		if err != nil {
+			return nil, "", err
+		}
+		return content, formatFromExtension(input), nil
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing input URL %q: %w", input, err)
+	}
+
+	loader, ok := loaders[u.Scheme]
+	if !ok {
+		return nil, "", fmt.Errorf("no loader registered for scheme %q", u.Scheme)
+	}
+
+	content, err := loader.Load(u)
+	if err != nil {
+		return nil, "", err
+	}
+
+	format := u.Query().Get("format")
+	if format == "" {
+		format = formatFromExtension(u.Path)
+	}
+
+	return content, format, nil
+}
+
+// formatFromExtension guesses a format hint from a path's file extension,
+// defaulting to "yaml" when the extension is unknown or absent.
+func formatFromExtension(path string) string {
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		return "json"
+	default:
+		return "yaml"
+	}
+}
+
+// fileLoader reads content from the local filesystem for file:// URLs.
+type fileLoader struct{}
+
+func (fileLoader) Load(u *url.URL) ([]byte, error) {
+	return os.ReadFile(u.Path)
+}
+
+// httpLoader downloads content over HTTP(S) for http:// and https:// URLs.
+type httpLoader struct{}
+
+func (httpLoader) Load(u *url.URL) ([]byte, error) {
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// stdinLoader reads content from standard input for stdin:// URLs, e.g.
+// "stdin://?format=json".
+type stdinLoader struct{}
+
+func (stdinLoader) Load(u *url.URL) ([]byte, error) {
+	return io.ReadAll(os.Stdin)
+}
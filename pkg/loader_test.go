@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatFromExtension(t *testing.T) {
+	cases := map[string]string{
+		"values.json":       "json",
+		"values.yaml":       "yaml",
+		"values.yml":        "yaml",
+		"values":            "yaml",
+		"stdin://?format=x": "yaml",
+	}
+	for in, want := range cases {
+		if got := formatFromExtension(in); got != want {
+			t.Errorf("formatFromExtension(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGetFileContentPlainPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(path, []byte("foo: bar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, format, err := getFileContent(path)
+	if err != nil {
+		t.Fatalf("getFileContent(%q) returned error: %v", path, err)
+	}
+	if string(content) != "foo: bar\n" {
+		t.Errorf("getFileContent(%q) content = %q", path, content)
+	}
+	if format != "yaml" {
+		t.Errorf("getFileContent(%q) format = %q, want %q", path, format, "yaml")
+	}
+}
+
+func TestGetFileContentFileScheme(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "values.json")
+	if err := os.WriteFile(path, []byte(`{"foo":"bar"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, format, err := getFileContent("file://" + path)
+	if err != nil {
+		t.Fatalf("getFileContent returned error: %v", err)
+	}
+	if string(content) != `{"foo":"bar"}` {
+		t.Errorf("unexpected content: %q", content)
+	}
+	if format != "json" {
+		t.Errorf("format = %q, want %q", format, "json")
+	}
+}
+
+func TestGetFileContentUnknownScheme(t *testing.T) {
+	if _, _, err := getFileContent("oci://example/chart"); err == nil {
+		t.Fatal("expected error for unregistered scheme, got nil")
+	}
+}
+
+func TestRegisterLoaderFormatOverride(t *testing.T) {
+	RegisterLoader("mem", memLoader{})
+	defer delete(loaders, "mem")
+
+	content, format, err := getFileContent("mem://anything?format=json")
+	if err != nil {
+		t.Fatalf("getFileContent returned error: %v", err)
+	}
+	if string(content) != "hello" {
+		t.Errorf("content = %q, want %q", content, "hello")
+	}
+	if format != "json" {
+		t.Errorf("format = %q, want %q (from query param)", format, "json")
+	}
+}
+
+type memLoader struct{}
+
+func (memLoader) Load(u *url.URL) ([]byte, error) {
+	return []byte("hello"), nil
+}
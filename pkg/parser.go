@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaAnnotationPrefix marks a comment line as a schema annotation, e.g.
+// "# @schema format=k8s-quantity,required=true".
+const schemaAnnotationPrefix = "@schema"
+
+// parseNode converts a single YAML mapping entry into a Schema, inferring
+// the JSON Schema type from the YAML value's kind/tag and then applying
+// any "@schema key=value,..." annotation attached to the key as a comment.
+func parseNode(keyNode, valNode *yaml.Node) (*Schema, bool) {
+	schema := &Schema{Type: yamlTypeToSchemaType(valNode)}
+
+	switch valNode.Kind {
+	case yaml.MappingNode:
+		schema.Type = "object"
+		schema.Properties = make(map[string]*Schema)
+		required := []string{}
+		for i := 0; i < len(valNode.Content); i += 2 {
+			childKey := valNode.Content[i]
+			childVal := valNode.Content[i+1]
+			childSchema, isRequired := parseNode(childKey, childVal)
+			schema.Properties[childKey.Value] = childSchema
+			if isRequired {
+				required = append(required, childKey.Value)
+			}
+		}
+		schema.Required = required
+	case yaml.SequenceNode:
+		schema.Type = "array"
+		if len(valNode.Content) > 0 {
+			itemSchema, _ := parseNode(valNode.Content[0], valNode.Content[0])
+			schema.Items = itemSchema
+		}
+	}
+
+	required := applyAnnotations(schema, keyNode)
+
+	return schema, required
+}
+
+// yamlTypeToSchemaType maps a scalar YAML node's tag to a JSON Schema
+// "type" value.
+func yamlTypeToSchemaType(node *yaml.Node) string {
+	switch node.Tag {
+	case "!!str":
+		return "string"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	default:
+		return "object"
+	}
+}
+
+// applyAnnotations parses the "@schema" comment attached to keyNode
+// (checked as both a head and a line comment, since YAML lets either
+// precede or trail the key depending on formatting) and applies each
+// recognized key=value pair to schema. It returns whether the property
+// was annotated as required.
+func applyAnnotations(schema *Schema, keyNode *yaml.Node) bool {
+	comment := keyNode.HeadComment
+	if keyNode.LineComment != "" {
+		comment += "\n" + keyNode.LineComment
+	}
+
+	required := false
+
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if !strings.HasPrefix(line, schemaAnnotationPrefix) {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, schemaAnnotationPrefix))
+
+		for _, pair := range strings.Split(line, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			value = strings.TrimSpace(value)
+
+			switch key {
+			case "type":
+				schema.Type = value
+			case "title":
+				schema.Title = value
+			case "description":
+				schema.Description = value
+			case "format":
+				schema.Format = value
+			case "required":
+				if b, err := strconv.ParseBool(value); err == nil {
+					required = b
+				}
+			case "additionalProperties":
+				if b, err := strconv.ParseBool(value); err == nil {
+					schema.AdditionalProperties = &b
+				}
+			}
+		}
+	}
+
+	return required
+}
@@ -0,0 +1,163 @@
+package pkg
+
+import "fmt"
+
+// Schema is an in-memory representation of a JSON Schema document (or
+// subschema), built up while walking a Helm values YAML file and later
+// flattened into a plain map by convertSchemaToMap.
+type Schema struct {
+	ID                   string
+	Ref                  string
+	Title                string
+	Description          string
+	Type                 string
+	Format               string
+	Properties           map[string]*Schema
+	Items                *Schema
+	Required             []string
+	Enum                 []interface{}
+	Default              interface{}
+	AdditionalProperties *bool
+}
+
+// mergeSchemas merges src into dst, preferring dst's own scalar fields
+// and recursively merging overlapping properties. dst is mutated and
+// returned; src is expected not to be reused afterwards.
+func mergeSchemas(dst, src *Schema) *Schema {
+	if dst == nil {
+		return src
+	}
+	if src == nil {
+		return dst
+	}
+
+	if dst.Type == "" {
+		dst.Type = src.Type
+	}
+	if dst.Title == "" {
+		dst.Title = src.Title
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.ID == "" {
+		dst.ID = src.ID
+	}
+	if dst.Format == "" {
+		dst.Format = src.Format
+	}
+	if dst.AdditionalProperties == nil {
+		dst.AdditionalProperties = src.AdditionalProperties
+	}
+
+	if len(src.Properties) > 0 {
+		if dst.Properties == nil {
+			dst.Properties = map[string]*Schema{}
+		}
+		for name, schema := range src.Properties {
+			dst.Properties[name] = mergeSchemas(dst.Properties[name], schema)
+		}
+	}
+
+	return dst
+}
+
+// convertSchemaToMap flattens a Schema into a plain map suitable for
+// marshaling as JSON Schema, omitting any field that was left at its
+// zero value.
+func convertSchemaToMap(s *Schema) (map[string]interface{}, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	m := map[string]interface{}{}
+
+	if s.ID != "" {
+		m["$id"] = s.ID
+	}
+	if s.Ref != "" {
+		m["$ref"] = s.Ref
+	}
+	if s.Title != "" {
+		m["title"] = s.Title
+	}
+	if s.Description != "" {
+		m["description"] = s.Description
+	}
+	if s.Type != "" {
+		m["type"] = s.Type
+	}
+	if s.Format != "" {
+		m["format"] = s.Format
+	}
+	if s.Default != nil {
+		m["default"] = s.Default
+	}
+	if len(s.Enum) > 0 {
+		m["enum"] = s.Enum
+	}
+	if len(s.Required) > 0 {
+		m["required"] = s.Required
+	}
+	if s.AdditionalProperties != nil {
+		m["additionalProperties"] = *s.AdditionalProperties
+	}
+
+	if len(s.Properties) > 0 {
+		properties := make(map[string]interface{}, len(s.Properties))
+		for name, propSchema := range s.Properties {
+			propMap, err := convertSchemaToMap(propSchema)
+			if err != nil {
+				return nil, err
+			}
+			properties[name] = propMap
+		}
+		m["properties"] = properties
+	}
+
+	if s.Items != nil {
+		itemsMap, err := convertSchemaToMap(s.Items)
+		if err != nil {
+			return nil, err
+		}
+		m["items"] = itemsMap
+	}
+
+	return m, nil
+}
+
+// getSchemaURL returns the "$schema" URL for the given JSON Schema draft
+// number.
+func getSchemaURL(draft int) (string, error) {
+	switch draft {
+	case 4:
+		return "http://json-schema.org/draft-04/schema#", nil
+	case 6:
+		return "http://json-schema.org/draft-06/schema#", nil
+	case 7:
+		return "http://json-schema.org/draft-07/schema#", nil
+	case 2019:
+		return "https://json-schema.org/draft/2019-09/schema", nil
+	case 2020:
+		return "https://json-schema.org/draft/2020-12/schema", nil
+	default:
+		return "", fmt.Errorf("unsupported schema draft: %d", draft)
+	}
+}
+
+// uniqueStringAppend appends each of additions to existing, skipping any
+// value already present, and returns the resulting slice.
+func uniqueStringAppend(existing []string, additions ...string) []string {
+	seen := make(map[string]bool, len(existing))
+	for _, v := range existing {
+		seen[v] = true
+	}
+	for _, v := range additions {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		existing = append(existing, v)
+	}
+	return existing
+}
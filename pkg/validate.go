@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"sigs.k8s.io/yaml"
+)
+
+// ValidateValues validates one or more Helm values files against a JSON
+// schema. If schemaPath is empty, a schema is generated on the fly from
+// config.Input instead of being loaded from disk. Values files are taken
+// from config.Validate and may be local paths or any URL scheme accepted
+// by getFileContent. It returns an error describing every failing
+// validation, so it can be run as a single CI step.
+func ValidateValues(config *Config, schemaPath string) error {
+	if len(config.Validate) == 0 {
+		return errors.New("validate flag is required")
+	}
+
+	schemaBytes, err := loadOrGenerateSchema(config, schemaPath)
+	if err != nil {
+		return err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+	if err := compiler.AddResource("schema.json", strings.NewReader(string(schemaBytes))); err != nil {
+		return fmt.Errorf("error loading schema: %w", err)
+	}
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		return fmt.Errorf("error compiling schema: %w", err)
+	}
+
+	var failures []string
+	for _, valuesPath := range config.Validate {
+		content, _, err := getFileContent(valuesPath)
+		if err != nil {
+			return fmt.Errorf("error reading values file %q: %w", valuesPath, err)
+		}
+
+		jsonBytes, err := yaml.YAMLToJSON(content)
+		if err != nil {
+			return fmt.Errorf("error converting %q to JSON: %w", valuesPath, err)
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(jsonBytes, &v); err != nil {
+			return fmt.Errorf("error unmarshaling %q: %w", valuesPath, err)
+		}
+
+		if err := schema.Validate(v); err != nil {
+			if verr, ok := err.(*jsonschema.ValidationError); ok {
+				for _, cause := range verr.Causes {
+					failures = append(failures, formatFailure(valuesPath, v, cause))
+				}
+			} else {
+				failures = append(failures, fmt.Sprintf("%s: %s", valuesPath, err))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("validation failed:\n%s", joinLines(failures))
+	}
+
+	fmt.Println("all values files are valid")
+
+	return nil
+}
+
+// loadOrGenerateSchema loads a previously generated schema from disk, or
+// generates one on the fly from config.Input when schemaPath is empty.
+func loadOrGenerateSchema(config *Config, schemaPath string) ([]byte, error) {
+	if schemaPath != "" {
+		content, _, err := getFileContent(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading schema %q: %w", schemaPath, err)
+		}
+		return content, nil
+	}
+
+	if err := GenerateJsonSchema(config); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(config.OutputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading generated schema: %w", err)
+	}
+
+	return content, nil
+}
+
+// formatFailure renders a single validation cause as "path: pointer:
+// message (value: ...)". cause.InstanceLocation is already an
+// RFC 6901 JSON pointer produced by the jsonschema library; the offending
+// value isn't carried on ValidationError, so it's looked up from the
+// validated document by walking that same pointer.
+func formatFailure(valuesPath string, root interface{}, cause *jsonschema.ValidationError) string {
+	pointer := cause.InstanceLocation
+	if pointer == "" {
+		pointer = "(root)"
+	}
+
+	if value, ok := resolvePointer(root, cause.InstanceLocation); ok {
+		valueJSON, err := json.Marshal(value)
+		if err == nil {
+			return fmt.Sprintf("%s: %s: %s (value: %s)", valuesPath, pointer, cause.Message, valueJSON)
+		}
+	}
+
+	return fmt.Sprintf("%s: %s: %s", valuesPath, pointer, cause.Message)
+}
+
+// resolvePointer resolves an RFC 6901 JSON pointer against root, returning
+// the value at that location. Segments are unescaped in reverse of the
+// jsonschema library's escape (URL-unescaped, then ~1 -> "/", then ~0 -> "~").
+func resolvePointer(root interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return root, true
+	}
+
+	cur := root
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		segment = unescapePointerSegment(segment)
+
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			value, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			cur = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			cur = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+func unescapePointerSegment(segment string) string {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		segment = unescaped
+	}
+	segment = strings.ReplaceAll(segment, "~1", "/")
+	segment = strings.ReplaceAll(segment, "~0", "~")
+	return segment
+}
+
+func joinLines(lines []string) string {
+	out := ""
+	for i, l := range lines {
+		if i > 0 {
+			out += "\n"
+		}
+		out += "  " + l
+	}
+	return out
+}
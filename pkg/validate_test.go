@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestResolvePointer(t *testing.T) {
+	root := map[string]interface{}{
+		"replicaCount": float64(3),
+		"image": map[string]interface{}{
+			"tag": "latest",
+		},
+		"ports": []interface{}{float64(80), float64(443)},
+	}
+
+	cases := []struct {
+		pointer string
+		want    interface{}
+		found   bool
+	}{
+		{"/replicaCount", float64(3), true},
+		{"/image/tag", "latest", true},
+		{"/ports/1", float64(443), true},
+		{"/missing", nil, false},
+		{"/ports/9", nil, false},
+	}
+
+	for _, c := range cases {
+		got, ok := resolvePointer(root, c.pointer)
+		if ok != c.found {
+			t.Errorf("resolvePointer(%q) found = %v, want %v", c.pointer, ok, c.found)
+			continue
+		}
+		if ok && got != c.want {
+			t.Errorf("resolvePointer(%q) = %v, want %v", c.pointer, got, c.want)
+		}
+	}
+
+	if got, ok := resolvePointer(root, ""); !ok {
+		t.Errorf("resolvePointer(root, \"\") found = %v, want true", ok)
+	} else if len(got.(map[string]interface{})) != len(root) {
+		t.Errorf("resolvePointer(root, \"\") = %v, want the root document", got)
+	}
+}
+
+func TestUnescapePointerSegment(t *testing.T) {
+	cases := map[string]string{
+		"key":     "key",
+		"a~1b":    "a/b",
+		"a~0b":    "a~b",
+		"a%20b":   "a b",
+		"a~1b~0c": "a/b~c",
+	}
+	for in, want := range cases {
+		if got := unescapePointerSegment(in); got != want {
+			t.Errorf("unescapePointerSegment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatFailureIncludesPointerAndValue(t *testing.T) {
+	root := map[string]interface{}{"replicaCount": float64(-1)}
+	cause := &jsonschema.ValidationError{
+		InstanceLocation: "/replicaCount",
+		Message:          "must be >= 0 but found -1",
+	}
+
+	got := formatFailure("values.yaml", root, cause)
+	for _, want := range []string{"values.yaml", "/replicaCount", "must be >= 0", "value: -1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatFailure() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestFormatFailureRootPointer(t *testing.T) {
+	root := map[string]interface{}{"replicaCount": float64(-1)}
+	cause := &jsonschema.ValidationError{Message: "missing properties: image"}
+
+	got := formatFailure("values.yaml", root, cause)
+	if !strings.Contains(got, "(root)") {
+		t.Errorf("formatFailure() = %q, want it to mention (root)", got)
+	}
+}
+
+func TestValidateValuesRejectsInvalidValues(t *testing.T) {
+	dir := t.TempDir()
+
+	schemaPath := filepath.Join(dir, "values.schema.json")
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 0}
+		}
+	}`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	valuesPath := filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(valuesPath, []byte("replicaCount: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{Validate: []string{valuesPath}}
+	err := ValidateValues(config, schemaPath)
+	if err == nil {
+		t.Fatal("expected validation error for negative replicaCount")
+	}
+	if !strings.Contains(err.Error(), "/replicaCount") {
+		t.Errorf("error = %v, want it to name the failing pointer", err)
+	}
+	if !strings.Contains(err.Error(), "value: -1") {
+		t.Errorf("error = %v, want it to include the offending value", err)
+	}
+}
+
+func TestValidateValuesAcceptsValidValues(t *testing.T) {
+	dir := t.TempDir()
+
+	schemaPath := filepath.Join(dir, "values.schema.json")
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 0}
+		}
+	}`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	valuesPath := filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(valuesPath, []byte("replicaCount: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{Validate: []string{valuesPath}}
+	if err := ValidateValues(config, schemaPath); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}